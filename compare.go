@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"log"
+	"strings"
+)
+
+// metricLabels gives each --metric value a human-readable chart title.
+var metricLabels = map[string]string{
+	"":           "Confirmed",
+	"confirmed":  "Confirmed",
+	"deaths":     "Deaths",
+	"recovered":  "Recovered",
+	"cfr":        "Case Fatality Rate",
+	"per_capita": "Cases per 100k",
+}
+
+// compareColorNames mirrors ui.StandardColors so the legend text can name
+// the fg color it assigned to each series.
+var compareColorNames = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+func parseCountryList(country string) []string {
+	parts := strings.Split(country, ",")
+	countries := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			countries = append(countries, p)
+		}
+	}
+	return countries
+}
+
+// metricSeries reduces a country's confirmed/deaths/recovered series down to
+// the single metric requested by --metric.
+func metricSeries(metric, country string, confirmed, deaths, recovered []float64) ([]float64, error) {
+	switch metric {
+	case "", "confirmed":
+		return confirmed, nil
+	case "deaths":
+		return deaths, nil
+	case "recovered":
+		return recovered, nil
+	case "cfr":
+		out := make([]float64, len(confirmed))
+		for i := range confirmed {
+			if confirmed[i] != 0 {
+				out[i] = deaths[i] / confirmed[i]
+			}
+		}
+		return out, nil
+	case "per_capita":
+		return perCapita(country, confirmed)
+	default:
+		return nil, fmt.Errorf("unknown metric %q, expected one of confirmed, deaths, recovered, cfr, per_capita", metric)
+	}
+}
+
+// DrawCompareChart draws a termui line chart with one series per country in
+// countryArg (a comma-separated list), plotting the metric selected by
+// --metric. A single "all" entry is preserved as the existing aggregate
+// across every tracked country, rather than being parsed as a country list.
+func DrawCompareChart(countryArg string, max int, metric, rng, smooth string) error {
+	if err := LoadData(context.Background()); err != nil {
+		return err
+	}
+
+	countries := parseCountryList(countryArg)
+	if len(countries) == 0 {
+		return errors.New("no country specified")
+	}
+
+	metricLabel, ok := metricLabels[metric]
+	if !ok {
+		metricLabel = metric
+	}
+
+	plotChart := widgets.NewPlot()
+	plotChart.Title = fmt.Sprintf("%s Chart [%s]", metricLabel, strings.Join(countries, ", "))
+	plotChart.PaddingBottom, plotChart.PaddingLeft, plotChart.PaddingRight, plotChart.PaddingTop = 1, 1, 1, 1
+	plotChart.Data = make([][]float64, len(countries))
+	plotChart.AxesColor = ui.ColorWhite
+	plotChart.Marker = widgets.MarkerBraille
+	plotChart.PlotType = widgets.LineChart
+	plotChart.DrawDirection = widgets.DrawRight
+	plotChart.HorizontalScale = 1
+	plotChart.DataLabels = countries
+
+	lineColors := make([]ui.Color, len(countries))
+	legendParts := make([]string, len(countries))
+	for i, country := range countries {
+		_, confirmed, deaths, recovered, err := prepareCountrySeries(country, max, rng, smooth)
+		if err != nil {
+			return err
+		}
+		series, err := metricSeries(metric, country, confirmed, deaths, recovered)
+		if err != nil {
+			return err
+		}
+		plotChart.Data[i] = series
+
+		lineColor := ui.SelectColor(ui.StandardColors, i)
+		lineColors[i] = lineColor
+		legendParts[i] = fmt.Sprintf("[%s](fg:%s)", country, compareColorNames[i%len(compareColorNames)])
+	}
+	plotChart.LineColors = lineColors
+
+	p := widgets.NewParagraph()
+	p.Text = "PRESS [q](fg:red) TO QUIT CHART | SERIES " + strings.Join(legendParts, ", ")
+	p.SetRect(0, 0, 25, 5)
+	p.BorderStyle.Fg = ui.ColorYellow
+
+	if err := ui.Init(); err != nil {
+		log.Fatalf("failed to initialize termui: %v", err)
+	}
+	defer ui.Close()
+
+	grid := ui.NewGrid()
+	termWidth, termHeight := ui.TerminalDimensions()
+	grid.SetRect(0, 0, termWidth, termHeight)
+
+	grid.Set(
+		ui.NewRow(1.0/10,
+			ui.NewCol(1.0, p),
+		),
+		ui.NewRow(9.0/10,
+			ui.NewCol(1.0, plotChart),
+		),
+	)
+
+	ui.Render(grid)
+
+	uiEvents := ui.PollEvents()
+	for {
+		e := <-uiEvents
+		switch e.ID {
+		case "q", "<C-c>":
+			return nil
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestMetricSeries(t *testing.T) {
+	confirmed := []float64{100, 200}
+	deaths := []float64{2, 8}
+	recovered := []float64{10, 20}
+
+	tests := []struct {
+		name    string
+		metric  string
+		country string
+		want    []float64
+		wantErr bool
+	}{
+		{name: "empty metric defaults to confirmed", metric: "", country: "US", want: confirmed},
+		{name: "confirmed", metric: "confirmed", country: "US", want: confirmed},
+		{name: "deaths", metric: "deaths", country: "US", want: deaths},
+		{name: "recovered", metric: "recovered", country: "US", want: recovered},
+		{name: "cfr divides deaths by confirmed", metric: "cfr", country: "US", want: []float64{0.02, 0.04}},
+		{name: "per_capita needs a known country", metric: "per_capita", country: "Nowhereland", wantErr: true},
+		{name: "unknown metric errors", metric: "bogus", country: "US", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := metricSeries(tt.metric, tt.country, confirmed, deaths, recovered)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("metricSeries() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("index %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPerCapita(t *testing.T) {
+	got, err := perCapita("Netherlands", []float64{171})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 1.0; len(got) != 1 || got[0] != want {
+		t.Errorf("perCapita() = %v, want [%v]", got, want)
+	}
+
+	if _, err := perCapita("Nowhereland", []float64{100}); err == nil {
+		t.Fatal("expected an error for a country with no population data, got nil")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DataSource fetches the current known timeseries for every country (and,
+// where the underlying provider supports it, sub-region) it knows about.
+// The returned map is keyed by country name, or "Country/Province" for
+// sources that report state/province level granularity.
+type DataSource interface {
+	Fetch(ctx context.Context) (map[string][]DayItem, error)
+}
+
+// sources is the registry of known DataSource implementations, keyed by the
+// name passed to the --source flag. New sources (e.g. WHO, OWID) register
+// themselves here via RegisterSource.
+var sources = map[string]DataSource{
+	"pomber": PomberSource{},
+	"jhu":    JHUSource{},
+}
+
+// RegisterSource adds (or replaces) a named DataSource in the registry.
+func RegisterSource(name string, source DataSource) {
+	sources[name] = source
+}
+
+// SourceName is set by the root --source flag and selects which DataSource
+// LoadData uses.
+var SourceName = "pomber"
+
+// dataMu guards AllValues, NameSortedCountries and ValueSortedCountries.
+// LoadData can run concurrently with reads of those globals (e.g. the
+// `serve` subcommand's background refresh ticker racing an in-flight
+// /table request), so every access to them goes through dataMu.
+var dataMu sync.RWMutex
+
+// LoadData fetches data from the selected DataSource and repopulates
+// AllValues along with the derived sorted country lists.
+func LoadData(ctx context.Context) error {
+	source, ok := sources[SourceName]
+	if !ok {
+		return fmt.Errorf("unknown data source %q", SourceName)
+	}
+
+	values, err := source.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	nameSorted := make([]string, 0, len(values))
+	for k := range values {
+		nameSorted = append(nameSorted, k)
+	}
+	sort.Strings(nameSorted)
+
+	valueSorted := make(PairList, 0, len(values))
+	for c, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		valueSorted = append(valueSorted, Pair{c, v[len(v)-1].Confirmed})
+	}
+	sort.Sort(valueSorted)
+
+	dataMu.Lock()
+	AllValues = values
+	NameSortedCountries = nameSorted
+	ValueSortedCountries = valueSorted
+	dataMu.Unlock()
+
+	return nil
+}
+
+// snapshotData returns the current country data and confirmed-count ranking
+// under a read lock. Since LoadData always swaps these in wholesale rather
+// than mutating them in place, the returned map/slice are safe to read
+// afterwards without continuing to hold the lock.
+func snapshotData() (map[string][]DayItem, PairList) {
+	dataMu.RLock()
+	defer dataMu.RUnlock()
+	return AllValues, ValueSortedCountries
+}
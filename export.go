@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"image/color"
+)
+
+// prepareCountrySeries assembles up to max days of confirmed/deaths/recovered
+// figures for country, oldest first, aggregating across every tracked
+// country when country is "all". rng narrows the window to a calendar-aware
+// preset (see filterByRange) and smooth applies a centered moving average
+// (see MovingAverage) to each series. It is shared by DrawCountryBarChart,
+// DrawCountryLineChart and ExportCountryChart so all three renderers feed
+// off the same data prep.
+func prepareCountrySeries(country string, max int, rng, smooth string) (labels []string, confirmed, deaths, recovered []float64, err error) {
+	smoothWindow, err := parseSmoothWindow(smooth)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	if country == "all" {
+		cMap := make(map[string]float64)
+		dMap := make(map[string]float64)
+		rMap := make(map[string]float64)
+		index := 0
+		allValues, _ := snapshotData()
+		for _, countryItem := range allValues {
+			filtered, ferr := filterByRange(countryItem, rng)
+			if ferr != nil {
+				return nil, nil, nil, nil, ferr
+			}
+			limit := max
+			if rng != "" && rng != "all" {
+				limit = len(filtered)
+			}
+			j := 0
+			for i := len(filtered) - 1; i >= 0; i-- {
+				j++
+				if index == 0 {
+					labels = append(labels, filtered[i].Date) // Add Labels only once
+				}
+				cMap[filtered[i].Date] = cMap[filtered[i].Date] + float64(filtered[i].Confirmed)
+				dMap[filtered[i].Date] = dMap[filtered[i].Date] + float64(filtered[i].Deaths)
+				rMap[filtered[i].Date] = rMap[filtered[i].Date] + float64(filtered[i].Recovered)
+				if j == limit {
+					break
+				}
+			}
+			index++
+		}
+		for _, label := range labels {
+			confirmed = append(confirmed, cMap[label])
+			deaths = append(deaths, dMap[label])
+			recovered = append(recovered, rMap[label])
+		}
+	} else {
+		countryItem, ok := resolveCountryItems(country)
+		if !ok {
+			return nil, nil, nil, nil, errors.New("country not found in dataset")
+		}
+		filtered, ferr := filterByRange(countryItem, rng)
+		if ferr != nil {
+			return nil, nil, nil, nil, ferr
+		}
+		limit := max
+		if rng != "" && rng != "all" {
+			limit = len(filtered)
+		}
+		j := 0
+		for i := len(filtered) - 1; i >= 0; i-- {
+			j++
+			labels = append(labels, filtered[i].Date)
+			confirmed = append(confirmed, float64(filtered[i].Confirmed))
+			deaths = append(deaths, float64(filtered[i].Deaths))
+			recovered = append(recovered, float64(filtered[i].Recovered))
+			if j == limit {
+				break
+			}
+		}
+	}
+
+	labels = ReverseStrings(labels)
+	confirmed = ReverseFloats(confirmed)
+	deaths = ReverseFloats(deaths)
+	recovered = ReverseFloats(recovered)
+
+	if smoothWindow > 0 {
+		confirmed = MovingAverage(confirmed, smoothWindow)
+		deaths = MovingAverage(deaths, smoothWindow)
+		recovered = MovingAverage(recovered, smoothWindow)
+	}
+
+	return labels, confirmed, deaths, recovered, nil
+}
+
+// ExportCountryChart renders the confirmed/deaths/recovered series for
+// country to an image file using gonum/plot. The file extension of out
+// selects the backend (.png, .svg, .pdf, ...).
+func ExportCountryChart(country string, max int, out string) error {
+	if err := LoadData(context.Background()); err != nil {
+		return err
+	}
+
+	labels, confirmed, deaths, recovered, err := prepareCountrySeries(country, max, "", "none")
+	if err != nil {
+		return err
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("Case, Death, Recoveries Chart [%s]", country)
+	p.X.Label.Text = "Date"
+	p.Y.Label.Text = "Count"
+	p.NominalX(labels...)
+	p.Legend.Top = true
+
+	series := []struct {
+		name  string
+		data  []float64
+		color color.RGBA
+	}{
+		{"Confirmed", confirmed, color.RGBA{R: 230, G: 200, B: 30, A: 255}},
+		{"Deaths", deaths, color.RGBA{R: 200, G: 30, B: 200, A: 255}},
+		{"Recovered", recovered, color.RGBA{R: 30, G: 180, B: 60, A: 255}},
+	}
+
+	for _, s := range series {
+		points := make(plotter.XYs, len(s.data))
+		for i, v := range s.data {
+			points[i].X = float64(i)
+			points[i].Y = v
+		}
+		line, err := plotter.NewLine(points)
+		if err != nil {
+			return err
+		}
+		line.LineStyle.Width = vg.Points(1.5)
+		line.LineStyle.Color = s.color
+		p.Add(line)
+		p.Legend.Add(s.name, line)
+	}
+
+	return p.Save(10*vg.Inch, 5*vg.Inch, out)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jhuBaseURI is the CSSE/JHU daily reports directory. Each day's snapshot
+// lives at its own MM-DD-YYYY.csv file rather than a single timeseries, so
+// building up history means walking backwards one day at a time.
+const jhuBaseURI = "https://raw.githubusercontent.com/CSSEGISandData/COVID-19/master/csse_covid_19_data/csse_covid_19_daily_reports"
+
+// jhuMaxDays bounds how many daily snapshots JHUSource will collect.
+const jhuMaxDays = 30
+
+// jhuMaxMisses bounds how many consecutive missing days JHUSource will skip
+// over (to absorb reporting lag) before it gives up walking further back.
+const jhuMaxMisses = 5
+
+var errDailyReportNotFound = errors.New("jhu: daily report not found")
+
+// JHUSource fetches CSSE/JHU daily reports, which break totals down by
+// Province/State as well as Country/Region. Rows are keyed into AllValues
+// as "Country/Province", or just "Country" when no province is reported.
+type JHUSource struct{}
+
+func (JHUSource) Fetch(ctx context.Context) (map[string][]DayItem, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	values := make(map[string][]DayItem)
+
+	date := time.Now()
+	found, misses := 0, 0
+	for found < jhuMaxDays && misses < jhuMaxMisses {
+		rows, err := fetchDailyReport(ctx, client, date)
+		if err == errDailyReportNotFound {
+			misses++
+			date = date.AddDate(0, 0, -1)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		misses = 0
+		found++
+		if err := appendDailyReport(values, date, rows); err != nil {
+			return nil, err
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+
+	// Days were collected newest-first; AllValues elsewhere assumes the
+	// last entry in a country's slice is the most recent day.
+	for key, items := range values {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		values[key] = items
+	}
+
+	return values, nil
+}
+
+// fetchDailyReport downloads and CSV-decodes the daily report for date,
+// returning errDailyReportNotFound when the file hasn't been published yet.
+func fetchDailyReport(ctx context.Context, client *http.Client, date time.Time) ([][]string, error) {
+	uri := fmt.Sprintf("%s/%s.csv", jhuBaseURI, date.Format("01-02-2006"))
+	request, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, errDailyReportNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jhu: unexpected status %s for %s", response.Status, uri)
+	}
+
+	reader := csv.NewReader(response.Body)
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// jhuColumns locates the columns this tool cares about within a daily
+// report's header row. The schema has changed over the course of the
+// pandemic (e.g. "Province/State" vs "Province_State"), so columns are
+// looked up by any of their known aliases rather than a fixed index.
+type jhuColumns struct {
+	province, country, confirmed, deaths, recovered, active, lat, long int
+}
+
+func findJHUColumns(header []string) jhuColumns {
+	index := func(names ...string) int {
+		for _, name := range names {
+			for i, h := range header {
+				if strings.EqualFold(strings.TrimSpace(h), name) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	return jhuColumns{
+		province:  index("Province/State", "Province_State"),
+		country:   index("Country/Region", "Country_Region"),
+		confirmed: index("Confirmed"),
+		deaths:    index("Deaths"),
+		recovered: index("Recovered"),
+		active:    index("Active"),
+		lat:       index("Lat", "Latitude"),
+		long:      index("Long_", "Long", "Longitude"),
+	}
+}
+
+// appendDailyReport parses one day's CSV rows and folds them into values,
+// keyed by "Country/Province" (or just "Country" when there's no province).
+func appendDailyReport(values map[string][]DayItem, date time.Time, rows [][]string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	columns := findJHUColumns(rows[0])
+	if columns.country == -1 || columns.confirmed == -1 {
+		return fmt.Errorf("jhu: could not find required columns in header %v", rows[0])
+	}
+
+	field := func(row []string, col int) string {
+		if col == -1 || col >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[col])
+	}
+	number := func(row []string, col int) int {
+		n, _ := strconv.Atoi(field(row, col))
+		return n
+	}
+	coordinate := func(row []string, col int) float64 {
+		f, _ := strconv.ParseFloat(field(row, col), 64)
+		return f
+	}
+
+	for _, row := range rows[1:] {
+		country := field(row, columns.country)
+		if country == "" {
+			continue
+		}
+		province := field(row, columns.province)
+
+		key := country
+		if province != "" {
+			key = country + "/" + province
+		}
+
+		values[key] = append(values[key], DayItem{
+			Date:      date.Format("2006-1-2"),
+			Confirmed: number(row, columns.confirmed),
+			Deaths:    number(row, columns.deaths),
+			Recovered: number(row, columns.recovered),
+			Active:    number(row, columns.active),
+			Province:  province,
+			Lat:       coordinate(row, columns.lat),
+			Long:      coordinate(row, columns.long),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindJHUColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		want   jhuColumns
+	}{
+		{
+			name:   "early-pandemic schema",
+			header: []string{"Province/State", "Country/Region", "Last Update", "Confirmed", "Deaths", "Recovered"},
+			want:   jhuColumns{province: 0, country: 1, confirmed: 3, deaths: 4, recovered: 5, active: -1, lat: -1, long: -1},
+		},
+		{
+			name:   "later schema with underscores and coordinates",
+			header: []string{"FIPS", "Admin2", "Province_State", "Country_Region", "Confirmed", "Deaths", "Recovered", "Active", "Lat", "Long_"},
+			want:   jhuColumns{province: 2, country: 3, confirmed: 4, deaths: 5, recovered: 6, active: 7, lat: 8, long: 9},
+		},
+		{
+			name:   "unrecognized header yields -1 for every column",
+			header: []string{"foo", "bar"},
+			want:   jhuColumns{province: -1, country: -1, confirmed: -1, deaths: -1, recovered: -1, active: -1, lat: -1, long: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findJHUColumns(tt.header)
+			if got != tt.want {
+				t.Errorf("findJHUColumns(%v) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendDailyReport(t *testing.T) {
+	date := time.Date(2020, 3, 5, 0, 0, 0, 0, time.UTC)
+	rows := [][]string{
+		{"Province/State", "Country/Region", "Confirmed", "Deaths", "Recovered"},
+		{"California", "US", "10", "1", "2"},
+		{"", "US", "20", "3", "4"},
+		{"", "", "100", "5", "6"},
+	}
+
+	values := make(map[string][]DayItem)
+	if err := appendDailyReport(values, date, rows); err != nil {
+		t.Fatalf("appendDailyReport() error = %v", err)
+	}
+
+	if got := len(values["US/California"]); got != 1 {
+		t.Fatalf(`values["US/California"] has %d items, want 1`, got)
+	}
+	if item := values["US/California"][0]; item.Confirmed != 10 || item.Province != "California" {
+		t.Errorf(`values["US/California"][0] = %+v, want Confirmed=10 Province="California"`, item)
+	}
+
+	if got := len(values["US"]); got != 1 {
+		t.Fatalf(`values["US"] has %d items, want 1 (blank-country row must be skipped)`, got)
+	}
+	if item := values["US"][0]; item.Confirmed != 20 || item.Province != "" {
+		t.Errorf(`values["US"][0] = %+v, want Confirmed=20 Province=""`, item)
+	}
+}
+
+func TestAppendDailyReportMissingRequiredColumns(t *testing.T) {
+	rows := [][]string{
+		{"Some Other Column"},
+		{"value"},
+	}
+	if err := appendDailyReport(make(map[string][]DayItem), time.Now(), rows); err == nil {
+		t.Fatal("expected an error when the header has no Country/Confirmed column, got nil")
+	}
+}
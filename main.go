@@ -1,32 +1,31 @@
 package main
 
 import (
-	"crypto/tls"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"github.com/buger/jsonparser"
 	"github.com/fatih/color"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 	_ "github.com/guptarohit/asciigraph"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/urfave/cli/v2"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"sort"
+	"strings"
 	"time"
 )
 
-const URI = "https://pomber.github.io/covid19/timeseries.json"
-
 type DayItem struct {
-	Date      string `json:"date"`
-	Confirmed int    `json:"confirmed"`
-	Deaths    int    `json:"deaths"`
-	Recovered int    `json:"recovered"`
+	Date      string  `json:"date"`
+	Confirmed int     `json:"confirmed"`
+	Deaths    int     `json:"deaths"`
+	Recovered int     `json:"recovered"`
+	Active    int     `json:"active,omitempty"`
+	Province  string  `json:"province,omitempty"`
+	Lat       float64 `json:"lat,omitempty"`
+	Long      float64 `json:"long,omitempty"`
 }
 
 type Pair struct {
@@ -53,6 +52,12 @@ func main() {
 			Usage:       "Use more verbosity",
 			Destination: &Verbose,
 		},
+		&cli.StringFlag{
+			Name:        "source",
+			Usage:       "Data source to use [pomber, jhu]",
+			Value:       "pomber",
+			Destination: &SourceName,
+		},
 	}
 	app := &cli.App{
 		Name:  "covid19",
@@ -63,14 +68,42 @@ func main() {
 			}
 			return nil
 		},
+		Before: func(c *cli.Context) error {
+			if _, ok := sources[SourceName]; !ok {
+				return fmt.Errorf("unknown data source %q", SourceName)
+			}
+			return nil
+		},
 		Flags: generalFlags,
 		Commands: []*cli.Command{
+			{
+				Name:    "serve",
+				Usage:   "Serve confirmed/deaths/recovered as Prometheus metrics",
+				Action: func(c *cli.Context) error {
+					if err := ServeMetrics(c.String("listen"), c.Duration("refresh")); err != nil {
+						color.Red("Error Serving Metrics, Details: %v", err)
+					}
+					return nil
+				},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "Address to listen on",
+						Value: ":9184",
+					},
+					&cli.DurationFlag{
+						Name:  "refresh",
+						Usage: "Interval to refresh metrics on",
+						Value: 10 * time.Minute,
+					},
+				},
+			},
 			{
 				Name:    "fetch",
 				Aliases: []string{"f"},
 				Usage:   "Fetch Data to Temp for further analysis, avoid fetch from web ",
 				Action: func(c *cli.Context) error {
-					if err := FetchData(true); err != nil {
+					if err := FetchRawPomberJSON(); err != nil {
 						color.Red("Error Fetching Data, Details: %v", err)
 					}
 					return nil
@@ -89,7 +122,7 @@ func main() {
 					// if inputCountry != "" {
 					// 	country = inputCountry
 					// }
-					if err := PrintCountry(country, c.Int("max")); err != nil {
+					if err := PrintCountry(country, c.Int("max"), c.String("range"), c.String("smooth")); err != nil {
 						color.Red("Error Parsing Data, Details: %v", err)
 					}
 					return nil
@@ -107,6 +140,15 @@ func main() {
 						Usage:   "Specify the country name to be used for summary",
 						Value:   "all",
 					},
+					&cli.StringFlag{
+						Name:  "range",
+						Usage: "Limit the new-case delta to a calendar window [7d, 30d, 3m, 6m, 1y, all]",
+					},
+					&cli.StringFlag{
+						Name:  "smooth",
+						Usage: "Smooth the new-case delta with a moving average [none, ma7, ma14]",
+						Value: "none",
+					},
 				},
 			},
 			{
@@ -116,13 +158,27 @@ func main() {
 				Action: func(c *cli.Context) error {
 					country := c.String("country")
 					drawType := c.String("type")
+					rng := c.String("range")
+					smooth := c.String("smooth")
+					if out := c.String("out"); out != "" {
+						if err := ExportCountryChart(country, c.Int("max"), out); err != nil {
+							color.Red("Error Parsing Data, Details: %v", err)
+						}
+						return nil
+					}
 					if drawType == "bar" {
-						if err := DrawCountryBarChart(country, c.Int("max")); err != nil {
+						if err := DrawCountryBarChart(country, c.Int("max"), rng, smooth); err != nil {
 							color.Red("Error Parsing Data, Details: %v", err)
 						}
 					}else{
-						if err := DrawCountryLineChart(country, c.Int("max")); err != nil {
-							color.Red("Error Parsing Data, Details: %v", err)
+						if len(parseCountryList(country)) > 1 {
+							if err := DrawCompareChart(country, c.Int("max"), c.String("metric"), rng, smooth); err != nil {
+								color.Red("Error Parsing Data, Details: %v", err)
+							}
+						} else {
+							if err := DrawCountryLineChart(country, c.Int("max"), rng, smooth); err != nil {
+								color.Red("Error Parsing Data, Details: %v", err)
+							}
 						}
 					}
 					return nil
@@ -137,7 +193,7 @@ func main() {
 					&cli.StringFlag{
 						Name:    "country",
 						Aliases: []string{"c"},
-						Usage:   "Specify the country name to be used for draw chart",
+						Usage:   "Specify the country name(s) to be used for draw chart, comma-separated for a comparison chart",
 						Value:   "all",
 					},
 					&cli.StringFlag{
@@ -146,6 +202,55 @@ func main() {
 						Usage:   "Specify chart type [bar, line]",
 						Value:   "bar",
 					},
+					&cli.StringFlag{
+						Name:  "metric",
+						Usage: "Metric to plot for line/comparison charts [confirmed, deaths, recovered, cfr, per_capita]",
+						Value: "confirmed",
+					},
+					&cli.StringFlag{
+						Name:  "out",
+						Usage: "Export the chart to a file instead of drawing it in the terminal (extension selects png/svg/pdf)",
+					},
+					&cli.StringFlag{
+						Name:  "range",
+						Usage: "Limit the chart to a calendar window [7d, 30d, 3m, 6m, 1y, all]",
+					},
+					&cli.StringFlag{
+						Name:  "smooth",
+						Usage: "Smooth the series with a moving average [none, ma7, ma14]",
+						Value: "none",
+					},
+				},
+			},
+			{
+				Name:    "export",
+				Aliases: []string{"e"},
+				Usage:   "Export confirmed/deaths/recovered chart to an image file",
+				Action: func(c *cli.Context) error {
+					if err := ExportCountryChart(c.String("country"), c.Int("max"), c.String("out")); err != nil {
+						color.Red("Error Parsing Data, Details: %v", err)
+					}
+					return nil
+				},
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:    "max",
+						Aliases: []string{"m"},
+						Usage:   "Maximum number of days to be printed form last day",
+						Value:   10,
+					},
+					&cli.StringFlag{
+						Name:    "country",
+						Aliases: []string{"c"},
+						Usage:   "Specify the country name to be used for draw chart",
+						Value:   "all",
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Output file path, extension selects png/svg/pdf",
+						Value:   "covid19.png",
+					},
 				},
 			},
 		},
@@ -161,82 +266,56 @@ func main() {
 	// fmt.Println(graph)
 }
 
-func FetchData(saveToTempOnly bool) error {
-	if Verbose {
-		color.Cyan("Fetching Data ...")
-	}
-	
-	client := http.Client{
-		Timeout: 3 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	request, err := http.NewRequest("GET", URI, nil)
-	if err != nil {
-		return err
-	}
-	response, err := client.Do(request)
-	if err != nil {
-		return err
+// resolveCountryItems looks up a country's DayItem history, falling back to
+// aggregating its sub-regions (e.g. "US/California", "US/New York") when no
+// exact key exists. This lets summary/chart accept either a plain country
+// name or a "Country/Province" key regardless of which DataSource is active.
+func resolveCountryItems(country string) ([]DayItem, bool) {
+	allValues, _ := snapshotData()
+	if items, ok := allValues[country]; ok {
+		return items, true
 	}
-	
-	arrayData, _ := ioutil.ReadAll(response.Body)
-	
-	if saveToTempOnly {
-		filename := fmt.Sprintf("%s%s", os.TempDir(), "covid_19_timeseries.json")
-		f, err := os.Create(filename)
-		if err != nil {
-			return err
-		}
-		_, err = f.Write(arrayData)
-		if err != nil {
-			return err
+
+	prefix := country + "/"
+	byDate := make(map[string]*DayItem)
+	var dates []string
+	for key, items := range allValues {
+		if !strings.HasPrefix(key, prefix) {
+			continue
 		}
-		err = f.Close()
-		if err != nil {
-			return err
+		for _, item := range items {
+			if existing, ok := byDate[item.Date]; ok {
+				existing.Confirmed += item.Confirmed
+				existing.Deaths += item.Deaths
+				existing.Recovered += item.Recovered
+				existing.Active += item.Active
+			} else {
+				merged := item
+				merged.Province = ""
+				byDate[item.Date] = &merged
+				dates = append(dates, item.Date)
+			}
 		}
-		color.Blue("File Fetched in path: %s", filename)
-		return nil
-	}
-	
-	if Verbose {
-		color.Blue("Parsing Data ...")
 	}
-	err = jsonparser.ObjectEach(arrayData, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
-		country := string(key)
-		countryItems := make([]DayItem, 0)
-		jsonparser.ArrayEach(value, func(arrValue []byte, dataType jsonparser.ValueType, offset int, err error) {
-			var dayItem DayItem
-			if err := json.Unmarshal(arrValue, &dayItem); err != nil {
-				log.Print(err)
-			}
-			countryItems = append(countryItems, dayItem)
-		})
-		AllValues[country] = countryItems
-		return nil
-	}, )
-	for k := range AllValues {
-		NameSortedCountries = append(NameSortedCountries, k)
+	if len(dates) == 0 {
+		return nil, false
 	}
-	sort.Strings(NameSortedCountries)
-	
-	for c, v := range AllValues {
-		ValueSortedCountries = append(ValueSortedCountries, Pair{c, v[len(v)-1].Confirmed})
+
+	sort.Strings(dates)
+	items := make([]DayItem, 0, len(dates))
+	for _, date := range dates {
+		items = append(items, *byDate[date])
 	}
-	sort.Sort(ValueSortedCountries)
-	return err
+	return items, true
 }
 
 func PrintSummary() error {
-	if err := FetchData(false); err != nil {
+	if err := LoadData(context.Background()); err != nil {
 		return err
 	}
+	allValues, _ := snapshotData()
 	Confirmed, Deaths, Recovered := 0, 0, 0
-	for _, items := range AllValues {
+	for _, items := range allValues {
 		lItem := items[len(items)-1]
 		Confirmed += lItem.Confirmed
 		Deaths += lItem.Deaths
@@ -254,36 +333,35 @@ func PrintSummary() error {
 	return nil
 }
 
-func PrintCountry(country string, max int) error {
-	if err := FetchData(false); err != nil {
+func PrintCountry(country string, max int, rng, smooth string) error {
+	if err := LoadData(context.Background()); err != nil {
 		return err
 	}
 	// TODO: Add Last Update Date
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.AppendHeader(table.Row{"Country", "Confirmed", "Deaths", "Recovered", "New Case", "New Deaths", "New Recoveries"})
-	
+
 	if country == "all" {
-		for idx, Pair := range ValueSortedCountries {
+		allValues, valueSorted := snapshotData()
+		for idx, Pair := range valueSorted {
 			if idx == max {
 				break
 			}
-			lItem := AllValues[Pair.Key][len(AllValues[Pair.Key])-1]
-			mlItem := AllValues[Pair.Key][len(AllValues[Pair.Key])-2]
-			newConfirmed := lItem.Confirmed - mlItem.Confirmed
-			newDeaths := lItem.Deaths - mlItem.Deaths
-			newRecovered := lItem.Recovered - mlItem.Recovered
+			lItem, newConfirmed, newDeaths, newRecovered, err := countryDelta(allValues[Pair.Key], rng, smooth)
+			if err != nil {
+				return err
+			}
 			t.AppendRows([]table.Row{
 				{Pair.Key, lItem.Confirmed, lItem.Deaths, lItem.Recovered, newConfirmed, newDeaths, newRecovered},
 			})
 		}
 	} else {
-		if countryItem, ok := AllValues[country]; ok {
-			lItem := countryItem[len(countryItem)-1]
-			mlItem := countryItem[len(countryItem)-2]
-			newConfirmed := lItem.Confirmed - mlItem.Confirmed
-			newDeaths := lItem.Deaths - mlItem.Deaths
-			newRecovered := lItem.Recovered - mlItem.Recovered
+		if countryItem, ok := resolveCountryItems(country); ok {
+			lItem, newConfirmed, newDeaths, newRecovered, err := countryDelta(countryItem, rng, smooth)
+			if err != nil {
+				return err
+			}
 			t.AppendRows([]table.Row{
 				{country, lItem.Confirmed, lItem.Deaths, lItem.Recovered, newConfirmed, newDeaths, newRecovered},
 			})
@@ -296,8 +374,8 @@ func PrintCountry(country string, max int) error {
 	return nil
 }
 
-func DrawCountryBarChart(country string, max int) error {
-	if err := FetchData(false); err != nil {
+func DrawCountryBarChart(country string, max int, rng, smooth string) error {
+	if err := LoadData(context.Background()); err != nil {
 		return err
 	}
 	
@@ -310,8 +388,6 @@ func DrawCountryBarChart(country string, max int) error {
 	rChart := widgets.NewBarChart()
 	rChart.Title = fmt.Sprintf("Recovered Chart [%s]", country)
 	
-	cData, dData,rData := make([]float64,0),make([]float64,0),make([]float64,0)
-	
 	cChart.BarColors = []ui.Color{ui.ColorYellow, }
 	dChart.BarColors = []ui.Color{ui.ColorMagenta}
 	rChart.BarColors = []ui.Color{ui.ColorGreen}
@@ -334,57 +410,11 @@ func DrawCountryBarChart(country string, max int) error {
 	
 	
 	
-	labels := make([]string,0)
-	
-	if country == "all" {
-		cMap := make(map[string]float64)
-		dMap := make(map[string]float64)
-		rMap := make(map[string]float64)
-		index:=0
-		for _, countryItem := range AllValues {
-			j :=0
-			for i:=len(countryItem)-1; i>=0; i-- {
-				j++
-				if index == 0 {
-					labels = append(labels, countryItem[i].Date) // Add Labels only once
-				}
-				cMap[countryItem[i].Date] = cMap[countryItem[i].Date] + float64(countryItem[i].Confirmed)
-				dMap[countryItem[i].Date] = dMap[countryItem[i].Date] + float64(countryItem[i].Deaths)
-				rMap[countryItem[i].Date] = dMap[countryItem[i].Date] + float64(countryItem[i].Recovered)
-				if j == max {
-					break
-				}
-			}
-			index++
-		}
-		for _,label := range labels {
-			cData = append(cData, cMap[label])
-			dData = append(dData, dMap[label])
-			rData = append(rData, rMap[label])
-		}
-	}else{
-		if countryItem, ok := AllValues[country]; ok {
-			j:=0
-			for i:=len(countryItem)-1; i>=0; i-- {
-				j++
-				labels = append(labels, countryItem[i].Date)
-				cData = append(cData, float64(countryItem[i].Confirmed))
-				dData = append(dData, float64(countryItem[i].Deaths))
-				rData = append(rData, float64(countryItem[i].Recovered))
-				if j == max {
-					break
-				}
-			}
-		} else {
-			return errors.New("country not found in dataset")
-		}
+	labels, cData, dData, rData, err := prepareCountrySeries(country, max, rng, smooth)
+	if err != nil {
+		return err
 	}
-	
-	labels = ReverseStrings(labels)
-	cData = ReverseFloats(cData)
-	dData = ReverseFloats(dData)
-	rData = ReverseFloats(rData)
-	
+
 	cChart.Labels = labels
 	dChart.Labels = labels
 	rChart.Labels = labels
@@ -434,14 +464,14 @@ func DrawCountryBarChart(country string, max int) error {
 	}
 }
 
-func DrawCountryLineChart(country string, max int) error {
-	if err := FetchData(false); err != nil {
+func DrawCountryLineChart(country string, max int, rng, smooth string) error {
+	if err := LoadData(context.Background()); err != nil {
 		return err
 	}
-	
+
 	plotChart := widgets.NewPlot()
 	plotChart.Title = fmt.Sprintf("Case, Death, Recoveries Chart [%s]", country)
-	
+
 	plotChart.PaddingBottom,plotChart.PaddingLeft,plotChart.PaddingRight, plotChart.PaddingTop = 1,1,1,1
 	plotChart.Data = make([][]float64, 3)
 	plotChart.AxesColor = ui.ColorWhite
@@ -451,69 +481,29 @@ func DrawCountryLineChart(country string, max int) error {
 	plotChart.DrawDirection = widgets.DrawRight
 	plotChart.HorizontalScale = 1
 	plotChart.DataLabels = []string{"Cased", "Deaths", "Recovered"}
-	
-	if country == "all" {
-		labels := make([]string,0)
-		cMap := make(map[string]float64)
-		dMap := make(map[string]float64)
-		rMap := make(map[string]float64)
-		index:=0
-		for _, countryItem := range AllValues {
-			j :=0
-			for i:=len(countryItem)-1; i>=0; i-- {
-				j++
-				if index == 0 {
-					labels = append(labels, countryItem[i].Date) // Add Labels only once
-				}
-				cMap[countryItem[i].Date] = cMap[countryItem[i].Date] + float64(countryItem[i].Confirmed)
-				dMap[countryItem[i].Date] = dMap[countryItem[i].Date] + float64(countryItem[i].Deaths)
-				rMap[countryItem[i].Date] = rMap[countryItem[i].Date] + float64(countryItem[i].Recovered)
-				if j == max {
-					break
-				}
-			}
-			index++
-		}
-		for _,label := range labels {
-			plotChart.Data[0] = append(plotChart.Data[0], cMap[label])
-			plotChart.Data[1] = append(plotChart.Data[1], dMap[label])
-			plotChart.Data[2] = append(plotChart.Data[2], rMap[label])
-		}
-	}else{
-		if countryItem, ok := AllValues[country]; ok {
-			j:=0
-			for i:=len(countryItem)-1; i>=0; i-- {
-				j++
-				plotChart.Data[0] = append(plotChart.Data[0], float64(countryItem[i].Confirmed))
-				plotChart.Data[1] = append(plotChart.Data[1], float64(countryItem[i].Deaths))
-				plotChart.Data[2] = append(plotChart.Data[2], float64(countryItem[i].Recovered))
-				if j == max {
-					break
-				}
-			}
-		} else {
-			return errors.New("country not found in dataset")
-		}
+
+	_, cData, dData, rData, err := prepareCountrySeries(country, max, rng, smooth)
+	if err != nil {
+		return err
 	}
-	
-	plotChart.Data[0] = ReverseFloats(plotChart.Data[0])
-	plotChart.Data[1] = ReverseFloats(plotChart.Data[1])
-	plotChart.Data[2] = ReverseFloats(plotChart.Data[2])
-	
+	plotChart.Data[0] = cData
+	plotChart.Data[1] = dData
+	plotChart.Data[2] = rData
+
 	p := widgets.NewParagraph()
 	p.Text = "PRESS [q](fg:red) TO QUIT CHART | SERIES [Cases](fg:yellow), [Deaths](fg:magenta), [Recovered](fg:green)"
 	p.SetRect(0, 0, 25, 5)
 	p.BorderStyle.Fg = ui.ColorYellow
-	
+
 	if err := ui.Init(); err != nil {
 		log.Fatalf("failed to initialize termui: %v", err)
 	}
 	defer ui.Close()
-	
+
 	grid := ui.NewGrid()
 	termWidth, termHeight := ui.TerminalDimensions()
 	grid.SetRect(0, 0, termWidth, termHeight)
-	
+
 	grid.Set(
 		ui.NewRow(1.0/10,
 			ui.NewCol(1.0,p ),
@@ -522,9 +512,9 @@ func DrawCountryLineChart(country string, max int) error {
 			ui.NewCol(1.0, plotChart),
 		),
 	)
-	
+
 	ui.Render(grid)
-	
+
 	uiEvents := ui.PollEvents()
 	for {
 		e := <-uiEvents
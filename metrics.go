@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+var (
+	confirmedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "covid19_confirmed",
+		Help: "Cumulative confirmed COVID-19 cases, by country",
+	}, []string{"country"})
+	deathsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "covid19_deaths",
+		Help: "Cumulative COVID-19 deaths, by country",
+	}, []string{"country"})
+	recoveredGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "covid19_recovered",
+		Help: "Cumulative COVID-19 recoveries, by country",
+	}, []string{"country"})
+	newConfirmedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "covid19_new_confirmed",
+		Help: "Confirmed cases reported since the previous reporting day, by country",
+	}, []string{"country"})
+)
+
+func init() {
+	prometheus.MustRegister(confirmedGauge, deathsGauge, recoveredGauge, newConfirmedGauge)
+}
+
+// refreshMetrics reloads AllValues from the selected DataSource and updates
+// the gauge vectors from the latest two reporting days.
+func refreshMetrics() error {
+	if err := LoadData(context.Background()); err != nil {
+		return err
+	}
+	allValues, _ := snapshotData()
+	for country, items := range allValues {
+		if len(items) == 0 {
+			continue
+		}
+		last := items[len(items)-1]
+		confirmedGauge.WithLabelValues(country).Set(float64(last.Confirmed))
+		deathsGauge.WithLabelValues(country).Set(float64(last.Deaths))
+		recoveredGauge.WithLabelValues(country).Set(float64(last.Recovered))
+
+		if len(items) >= 2 {
+			prev := items[len(items)-2]
+			newConfirmedGauge.WithLabelValues(country).Set(float64(last.Confirmed - prev.Confirmed))
+		}
+	}
+	return nil
+}
+
+// ServeMetrics runs a long-lived HTTP server exposing /metrics, /healthz
+// and /table, refreshing AllValues on the given interval.
+func ServeMetrics(listen string, refresh time.Duration) error {
+	if err := refreshMetrics(); err != nil {
+		color.Red("Error Fetching Data, Details: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := refreshMetrics(); err != nil {
+				color.Red("Error Refreshing Data, Details: %v", err)
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/table", handleTable)
+
+	color.Blue("Serving metrics on %s (refreshing every %s)", listen, refresh)
+	return http.ListenAndServe(listen, mux)
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// handleTable renders the same summary as `covid19 summary -c all` as an
+// HTML table, so it can be embedded behind a reverse proxy.
+func handleTable(w http.ResponseWriter, r *http.Request) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Country", "Confirmed", "Deaths", "Recovered", "New Confirmed"})
+	allValues, valueSorted := snapshotData()
+	for _, pair := range valueSorted {
+		items := allValues[pair.Key]
+		if len(items) == 0 {
+			continue
+		}
+		last := items[len(items)-1]
+		newConfirmed := 0
+		if len(items) >= 2 {
+			newConfirmed = last.Confirmed - items[len(items)-2].Confirmed
+		}
+		t.AppendRow(table.Row{pair.Key, last.Confirmed, last.Deaths, last.Recovered, newConfirmed})
+	}
+	t.SetStyle(table.StyleLight)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, t.RenderHTML())
+}
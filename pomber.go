@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/buger/jsonparser"
+	"github.com/fatih/color"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// pomberURI is the full timeseries endpoint used by both FetchRawPomberJSON
+// and PomberSource.
+const pomberURI = "https://pomber.github.io/covid19/timeseries.json"
+
+// newInsecureHTTPClient returns the short-timeout, TLS-verification-skipping
+// client used for the pomber endpoint (unchanged from the original FetchData).
+func newInsecureHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 3 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+}
+
+// PomberSource fetches the pomber/covid19 timeseries JSON, which reports a
+// single daily figure per country with no province/state breakdown.
+type PomberSource struct{}
+
+func (PomberSource) Fetch(ctx context.Context) (map[string][]DayItem, error) {
+	client := newInsecureHTTPClient()
+	request, err := http.NewRequestWithContext(ctx, "GET", pomberURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	arrayData, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if Verbose {
+		color.Blue("Parsing Data ...")
+	}
+
+	values := make(map[string][]DayItem)
+	err = jsonparser.ObjectEach(arrayData, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		country := string(key)
+		countryItems := make([]DayItem, 0)
+		jsonparser.ArrayEach(value, func(arrValue []byte, dataType jsonparser.ValueType, offset int, err error) {
+			var dayItem DayItem
+			if err := json.Unmarshal(arrValue, &dayItem); err != nil {
+				log.Print(err)
+			}
+			countryItems = append(countryItems, dayItem)
+		})
+		values[country] = countryItems
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// FetchRawPomberJSON downloads the raw pomber timeseries JSON and saves it
+// to a temp file, so it can be inspected or replayed without hitting the
+// network again. This is independent of the DataSource abstraction since it
+// caches the raw response rather than a parsed one.
+func FetchRawPomberJSON() error {
+	if Verbose {
+		color.Cyan("Fetching Data ...")
+	}
+
+	client := newInsecureHTTPClient()
+	request, err := http.NewRequest("GET", pomberURI, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	arrayData, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s%s", os.TempDir(), "covid_19_timeseries.json")
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(arrayData); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	color.Blue("File Fetched in path: %s", filename)
+	return nil
+}
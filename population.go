@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// populationJSON is a small reference table of country populations (in
+// millions), keyed by country name, used to compute the per_capita metric.
+const populationJSON = `{
+	"US": 331.0,
+	"China": 1439.0,
+	"India": 1380.0,
+	"Brazil": 212.0,
+	"Russia": 146.0,
+	"Germany": 83.8,
+	"France": 65.3,
+	"United Kingdom": 67.9,
+	"Italy": 60.5,
+	"Spain": 46.8,
+	"Iran": 84.0,
+	"South Korea": 51.3,
+	"Japan": 126.5,
+	"Canada": 37.7,
+	"Australia": 25.5,
+	"Mexico": 129.0,
+	"Turkey": 84.3,
+	"Indonesia": 273.5,
+	"Saudi Arabia": 34.8,
+	"Netherlands": 17.1
+}`
+
+var population = mustParsePopulation(populationJSON)
+
+func mustParsePopulation(data string) map[string]float64 {
+	var m map[string]float64
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		panic(fmt.Sprintf("population: invalid embedded JSON: %v", err))
+	}
+	return m
+}
+
+// perCapita converts cumulative counts into cases-per-100k using the
+// population table, returning an error if country isn't in it.
+func perCapita(country string, counts []float64) ([]float64, error) {
+	pop, ok := population[country]
+	if !ok {
+		return nil, fmt.Errorf("no population data for %q, cannot compute per_capita", country)
+	}
+	out := make([]float64, len(counts))
+	for i, v := range counts {
+		out[i] = v / (pop * 10)
+	}
+	return out, nil
+}
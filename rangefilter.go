@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// dayItemDateLayout matches the non-zero-padded dates used by DayItem.Date,
+// e.g. "2020-3-5".
+const dayItemDateLayout = "2006-1-2"
+
+func parseDayItemDate(date string) (time.Time, error) {
+	return time.Parse(dayItemDateLayout, date)
+}
+
+// filterByRange returns the items falling on or after the cutoff implied by
+// rng, measured back from the most recent item's date. An empty rng (or
+// "all") returns items unchanged. items must be sorted oldest-first.
+func filterByRange(items []DayItem, rng string) ([]DayItem, error) {
+	if rng == "" || rng == "all" || len(items) == 0 {
+		return items, nil
+	}
+
+	last, err := parseDayItemDate(items[len(items)-1].Date)
+	if err != nil {
+		return nil, err
+	}
+
+	var cutoff time.Time
+	switch rng {
+	case "7d":
+		cutoff = last.AddDate(0, 0, -7)
+	case "30d":
+		cutoff = last.AddDate(0, 0, -30)
+	case "3m":
+		cutoff = last.AddDate(0, -3, 0)
+	case "6m":
+		cutoff = last.AddDate(0, -6, 0)
+	case "1y":
+		cutoff = last.AddDate(-1, 0, 0)
+	default:
+		return nil, fmt.Errorf("unknown range %q, expected one of 7d, 30d, 3m, 6m, 1y, all", rng)
+	}
+
+	filtered := make([]DayItem, 0, len(items))
+	for _, item := range items {
+		d, err := parseDayItemDate(item.Date)
+		if err != nil {
+			continue
+		}
+		if !d.Before(cutoff) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
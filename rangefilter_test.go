@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestFilterByRange(t *testing.T) {
+	items := []DayItem{
+		{Date: "2020-1-1", Confirmed: 1},
+		{Date: "2020-1-15", Confirmed: 2},
+		{Date: "2020-1-24", Confirmed: 3},
+		{Date: "2020-1-25", Confirmed: 4},
+		{Date: "2020-1-31", Confirmed: 5},
+	}
+
+	tests := []struct {
+		name    string
+		rng     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "empty range returns everything unchanged",
+			rng:  "",
+			want: []string{"2020-1-1", "2020-1-15", "2020-1-24", "2020-1-25", "2020-1-31"},
+		},
+		{
+			name: "all returns everything unchanged",
+			rng:  "all",
+			want: []string{"2020-1-1", "2020-1-15", "2020-1-24", "2020-1-25", "2020-1-31"},
+		},
+		{
+			name: "7d includes the cutoff day itself",
+			rng:  "7d",
+			want: []string{"2020-1-24", "2020-1-25", "2020-1-31"},
+		},
+		{
+			name:    "unknown range errors",
+			rng:     "2w",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterByRange(items, tt.rng)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterByRange() = %v, want dates %v", got, tt.want)
+			}
+			for i, item := range got {
+				if item.Date != tt.want[i] {
+					t.Errorf("index %d: got date %q, want %q", i, item.Date, tt.want[i])
+				}
+			}
+		})
+	}
+}
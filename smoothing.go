@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"math"
+)
+
+// parseSmoothWindow maps a --smooth flag value to a MovingAverage window
+// size, or 0 for "none" (no smoothing).
+func parseSmoothWindow(smooth string) (int, error) {
+	switch smooth {
+	case "", "none":
+		return 0, nil
+	case "ma7":
+		return 7, nil
+	case "ma14":
+		return 14, nil
+	default:
+		return 0, fmt.Errorf("unknown smoothing %q, expected one of none, ma7, ma14", smooth)
+	}
+}
+
+// MovingAverage returns the centered moving average of in over the given
+// window. The leading (window-1)/2 points, which don't have enough history
+// to center a window on, are set to NaN so callers (e.g. the termui plot)
+// render them as gaps rather than misleading partial averages.
+func MovingAverage(in []float64, window int) []float64 {
+	if window <= 1 || window > len(in) {
+		return in
+	}
+
+	out := make([]float64, len(in))
+	lead := (window - 1) / 2
+	for i := range out {
+		if i < lead || i-lead+window > len(in) {
+			out[i] = math.NaN()
+			continue
+		}
+		sum := 0.0
+		for _, v := range in[i-lead : i-lead+window] {
+			sum += v
+		}
+		out[i] = sum / float64(window)
+	}
+	return out
+}
+
+// lastTwoFinite returns the indices of the two most recent non-NaN points in
+// series, most recent first. ok is false if fewer than two exist.
+func lastTwoFinite(series []float64) (last, prev int, ok bool) {
+	last = -1
+	for i := len(series) - 1; i >= 0; i-- {
+		if math.IsNaN(series[i]) {
+			continue
+		}
+		if last == -1 {
+			last = i
+			continue
+		}
+		return last, i, true
+	}
+	return 0, 0, false
+}
+
+// countryDelta narrows items to rng (falling back to the full history if
+// that leaves too little to compare), then reports the most recent day's
+// totals alongside the day-over-day change, optionally smoothed per smooth
+// to remove weekday reporting noise.
+func countryDelta(items []DayItem, rng, smooth string) (current DayItem, newConfirmed, newDeaths, newRecovered int, err error) {
+	filtered, err := filterByRange(items, rng)
+	if err != nil {
+		return DayItem{}, 0, 0, 0, err
+	}
+	if len(filtered) < 2 {
+		if Verbose && rng != "" && rng != "all" {
+			color.Yellow("range %q left too few points to compare, falling back to full history", rng)
+		}
+		filtered = items
+	}
+	if len(filtered) < 2 {
+		return filtered[len(filtered)-1], 0, 0, 0, nil
+	}
+
+	confirmed := make([]float64, len(filtered))
+	deaths := make([]float64, len(filtered))
+	recovered := make([]float64, len(filtered))
+	for i, item := range filtered {
+		confirmed[i] = float64(item.Confirmed)
+		deaths[i] = float64(item.Deaths)
+		recovered[i] = float64(item.Recovered)
+	}
+
+	window, err := parseSmoothWindow(smooth)
+	if err != nil {
+		return DayItem{}, 0, 0, 0, err
+	}
+	if window > 0 {
+		confirmed = MovingAverage(confirmed, window)
+		deaths = MovingAverage(deaths, window)
+		recovered = MovingAverage(recovered, window)
+	}
+
+	last, prev, ok := lastTwoFinite(confirmed)
+	if !ok {
+		return filtered[len(filtered)-1], 0, 0, 0, nil
+	}
+
+	current = filtered[len(filtered)-1]
+	newConfirmed = int(confirmed[last] - confirmed[prev])
+	newDeaths = int(deaths[last] - deaths[prev])
+	newRecovered = int(recovered[last] - recovered[prev])
+	return current, newConfirmed, newDeaths, newRecovered, nil
+}
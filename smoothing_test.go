@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     []float64
+		window int
+		want   []float64
+	}{
+		{
+			name:   "window of 1 is a no-op",
+			in:     []float64{1, 2, 3, 4},
+			window: 1,
+			want:   []float64{1, 2, 3, 4},
+		},
+		{
+			name:   "window larger than input is a no-op",
+			in:     []float64{1, 2, 3},
+			window: 10,
+			want:   []float64{1, 2, 3},
+		},
+		{
+			name:   "odd window centers on each point",
+			in:     []float64{1, 2, 3, 4, 5},
+			window: 3,
+			want:   []float64{math.NaN(), 2, 3, 4, math.NaN()},
+		},
+		{
+			name:   "even window has no lead but still gaps the trailing edge",
+			in:     []float64{1, 2, 3, 4, 5, 6},
+			window: 2,
+			want:   []float64{1.5, 2.5, 3.5, 4.5, 5.5, math.NaN()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MovingAverage(tt.in, tt.window)
+			if len(got) != len(tt.want) {
+				t.Fatalf("MovingAverage() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.IsNaN(tt.want[i]) {
+					if !math.IsNaN(got[i]) {
+						t.Errorf("index %d: got %v, want NaN", i, got[i])
+					}
+					continue
+				}
+				if got[i] != tt.want[i] {
+					t.Errorf("index %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}